@@ -0,0 +1,119 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"fmt"
+
+	"github.com/containerd/containerd/v2/cmd/ctr/commands"
+	"github.com/containerd/imgcrypt/cmd/ctr/commands/flags"
+	"github.com/containerd/imgcrypt/images/encryption"
+	"github.com/containerd/imgcrypt/images/encryption/parsehelpers"
+	"github.com/urfave/cli/v2"
+)
+
+// copyCommand copies an image from one local reference to another, optionally
+// changing who it is encrypted for along the way. With --recipient (or
+// another flag from "ctr images encrypt"'s recipient set), the per-layer
+// symmetric keys are unwrapped using a supplied decryption key and rewrapped
+// to add the new recipients, without ever re-reading or re-encrypting layer
+// content. ocicrypt only ever adds wrapped keys to a layer's annotation, it
+// does not remove them, so there is no "strip this recipient" operation
+// short of decrypting and re-encrypting from scratch.
+var copyCommand = cli.Command{
+	Name:      "copy",
+	Usage:     "copy an image from one reference to another, optionally decrypting or adding recipients to it",
+	ArgsUsage: "[flags] <src> <dst>",
+	Description: `Copy an image from one local reference to another.
+
+With --decrypt (plus a decryption flag from "ctr images import"'s decryption
+flag set), the copy is fully decrypted: every layer is unwrapped using the
+given key(s), proving possession of a decryption key for the whole image.
+
+With --recipient (or another flag from "ctr images encrypt"'s recipient set),
+the copy is instead rewrapped to add the new recipient(s): the existing
+per-layer symmetric keys are recovered using a supplied decryption key and
+wrapped again for the additional recipients, so both the old and new
+recipients can decrypt the result. This still requires a decryption key able
+to unwrap the source image, same as --decrypt; the two flags are mutually
+exclusive.
+`,
+	Flags: append(append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:  "decrypt",
+			Usage: "fully decrypt the image, proving possession of a decryption key for every layer",
+		},
+	}, flags.ImageDecryptionFlags...), flags.ImageEncryptionFlags...),
+	Action: func(context *cli.Context) error {
+		src := context.Args().Get(0)
+		dst := context.Args().Get(1)
+		if src == "" || dst == "" {
+			return fmt.Errorf("please provide a source and destination reference")
+		}
+
+		ea := ParseEncArgs(context)
+		decrypt := context.Bool("decrypt")
+		addRecipients := len(ea.Recipient) > 0
+		if decrypt && addRecipients {
+			return fmt.Errorf("--decrypt and --recipient are mutually exclusive")
+		}
+
+		client, ctx, cancel, err := commands.NewClient(context)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		imageStore := client.ImageService()
+		srcImg, err := imageStore.Get(ctx, src)
+		if err != nil {
+			return fmt.Errorf("unable to resolve %v: %w", src, err)
+		}
+
+		newTarget := srcImg.Target
+		switch {
+		case decrypt:
+			cc, err := parsehelpers.CreateDecryptCryptoConfig(ea, nil)
+			if err != nil {
+				return err
+			}
+			newTarget, err = encryption.DecryptImage(ctx, client.ContentStore(), newTarget, cc.DecryptConfig)
+			if err != nil {
+				return fmt.Errorf("unable to decrypt %v: %w", src, err)
+			}
+		case addRecipients:
+			cc, err := parsehelpers.CreateCryptoConfig(ea, nil)
+			if err != nil {
+				return err
+			}
+			newTarget, err = encryption.EncryptImage(ctx, client.ContentStore(), newTarget, &cc)
+			if err != nil {
+				return fmt.Errorf("unable to add recipients to %v: %w", src, err)
+			}
+		}
+
+		dstImg := srcImg
+		dstImg.Name = dst
+		dstImg.Target = newTarget
+		if _, err := imageStore.Create(ctx, dstImg); err != nil {
+			return fmt.Errorf("unable to create %v: %w", dst, err)
+		}
+
+		fmt.Println(dst)
+		return nil
+	},
+}
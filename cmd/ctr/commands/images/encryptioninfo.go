@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/imgcrypt/images/encryption"
+	"github.com/containerd/imgcrypt/internal/format"
+	"github.com/containerd/platforms"
+)
+
+// encryptedMediaTypeSuffix marks an image-spec media type as wrapped by
+// ocicrypt, e.g. "application/vnd.oci.image.layer.v1.tar+gzip+encrypted".
+const encryptedMediaTypeSuffix = "+encrypted"
+
+// buildImageInfo assembles the stable, encryption-aware format.ImageInfo
+// used by "images list --format", "images check --format" and
+// "images inspect" from a single image's manifest and content store.
+func buildImageInfo(ctx context.Context, client *containerd.Client, image images.Image) (format.ImageInfo, error) {
+	cs := client.ContentStore()
+
+	info := format.ImageInfo{
+		Ref:    image.Name,
+		Digest: image.Target.Digest.String(),
+		Labels: image.Labels,
+	}
+
+	if size, err := image.Size(ctx, cs, platforms.Default()); err == nil {
+		info.Size = size
+	}
+
+	if specs, err := images.Platforms(ctx, cs, image.Target); err == nil {
+		seen := map[string]struct{}{}
+		for _, p := range specs {
+			s := platforms.Format(p)
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+			info.Platforms = append(info.Platforms, s)
+		}
+		sort.Strings(info.Platforms)
+	}
+
+	manifest, err := images.Manifest(ctx, cs, image.Target, platforms.Default())
+	if err != nil {
+		// Content for this platform may not be present locally yet; report
+		// what we know without layer-level encryption detail.
+		return info, nil
+	}
+
+	for _, layer := range manifest.Layers {
+		li := format.LayerInfo{
+			MediaType: string(layer.MediaType),
+			Encrypted: strings.HasSuffix(string(layer.MediaType), encryptedMediaTypeSuffix),
+		}
+		if li.Encrypted {
+			info.Encrypted = true
+			li.Scheme = encryption.GetLayerScheme(layer)
+			li.Recipients = encryption.GetLayerRecipients(layer)
+		}
+		info.Layers = append(info.Layers, li)
+	}
+
+	return info, nil
+}
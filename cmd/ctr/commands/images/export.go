@@ -0,0 +1,116 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containerd/containerd/v2/cmd/ctr/commands"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/archive"
+	"github.com/containerd/platforms"
+	"github.com/urfave/cli/v2"
+)
+
+var exportCommand = cli.Command{
+	Name:      "export",
+	Usage:     "export images, as either a tar stream or an OCI image layout directory",
+	ArgsUsage: "[flags] <out> <ref> [<ref>, ...]",
+	Description: `Export one or more images by reference to out, an OCI-compatible tar
+stream readable by "ctr images import" (this or any other OCI-aware tool),
+or, with --oci-layout, an OCI Image Layout directory. Encrypted layers are
+exported exactly as stored: content is neither decrypted nor re-wrapped, so
+no decryption key is required to export.
+`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "platform",
+			Usage: "export content for a specific platform",
+		},
+		&cli.BoolFlag{
+			Name:  "all-platforms",
+			Usage: "export content for all platforms, not just the default",
+		},
+		&cli.BoolFlag{
+			Name:  "oci-layout",
+			Usage: "write out as an OCI image layout directory instead of a tar stream",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		var (
+			out  = context.Args().First()
+			refs = context.Args().Tail()
+		)
+		if out == "" {
+			return fmt.Errorf("please specify an output path")
+		}
+		if len(refs) == 0 {
+			return fmt.Errorf("please specify at least one image reference")
+		}
+
+		client, ctx, cancel, err := commands.NewClient(context)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		allPlatforms := context.Bool("all-platforms")
+		var platformMatcher platforms.MatchComparer
+		if !allPlatforms {
+			if p := context.String("platform"); p != "" {
+				platSpec, err := platforms.Parse(p)
+				if err != nil {
+					return err
+				}
+				platformMatcher = platforms.OnlyStrict(platSpec)
+			} else {
+				platformMatcher = platforms.Default()
+			}
+		}
+
+		imageStore := client.ImageService()
+		var imgs []images.Image
+		for _, ref := range refs {
+			img, err := imageStore.Get(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("unable to resolve %v: %w", ref, err)
+			}
+			imgs = append(imgs, img)
+		}
+
+		if context.Bool("oci-layout") {
+			return exportOCILayout(ctx, client, out, imgs, platformMatcher, allPlatforms)
+		}
+
+		var exportOpts []archive.ExportOpt
+		if platformMatcher != nil {
+			exportOpts = append(exportOpts, archive.WithPlatform(platformMatcher))
+		}
+		for _, img := range imgs {
+			exportOpts = append(exportOpts, archive.WithImage(imageStore, img.Name))
+		}
+
+		w, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("unable to create %v: %w", out, err)
+		}
+		defer w.Close()
+
+		return client.Export(ctx, w, exportOpts...)
+	},
+}
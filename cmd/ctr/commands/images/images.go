@@ -28,6 +28,7 @@ import (
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/pkg/progress"
 	"github.com/containerd/errdefs"
+	"github.com/containerd/imgcrypt/internal/format"
 	"github.com/containerd/log"
 	"github.com/containerd/platforms"
 	"github.com/urfave/cli/v2"
@@ -40,14 +41,17 @@ var Command = &cli.Command{
 	Usage:   "manage images",
 	Subcommands: cli.Commands{
 		&checkCommand,
+		&copyCommand,
 		&exportCommand,
 		&importCommand,
+		&inspectCommand,
 		&listCommand,
 		&mountCommand,
 		&unmountCommand,
 		&pullCommand,
 		&pushCommand,
 		&removeCommand,
+		&pruneCommand,
 		&tagCommand,
 		&setLabelsCommand,
 		&convertCommand,
@@ -69,11 +73,17 @@ var listCommand = cli.Command{
 			Aliases: []string{"q"},
 			Usage:   "print only the image refs",
 		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: table (default), wide, json, or a Go template; includes per-layer encryption metadata",
+			Value: format.Table,
+		},
 	},
 	Action: func(context *cli.Context) error {
 		var (
 			filters = context.Args().Slice()
 			quiet   = context.Bool("quiet")
+			f       = context.String("format")
 		)
 		client, ctx, cancel, err := commands.NewClient(context)
 		if err != nil {
@@ -94,8 +104,29 @@ var listCommand = cli.Command{
 			}
 			return nil
 		}
+
+		if f == format.JSON || format.IsTemplate(f) {
+			infos := make([]format.ImageInfo, 0, len(imageList))
+			for _, image := range imageList {
+				info, err := buildImageInfo(ctx, client, image)
+				if err != nil {
+					return err
+				}
+				infos = append(infos, info)
+			}
+			if f == format.JSON {
+				return format.WriteJSON(os.Stdout, infos)
+			}
+			return format.WriteTemplate(os.Stdout, f, infos)
+		}
+
+		wide := f == format.Wide
 		tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, ' ', 0)
-		fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSIZE\tPLATFORMS\tLABELS\t")
+		if wide {
+			fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSIZE\tPLATFORMS\tLABELS\tENCRYPTED\t")
+		} else {
+			fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSIZE\tPLATFORMS\tLABELS\t")
+		}
 		for _, image := range imageList {
 			size, err := image.Size(ctx, cs, platforms.Default())
 			if err != nil {
@@ -129,6 +160,22 @@ var listCommand = cli.Command{
 				labels = strings.Join(pairs, ",")
 			}
 
+			if wide {
+				info, err := buildImageInfo(ctx, client, image)
+				if err != nil {
+					log.G(ctx).WithError(err).Errorf("failed resolving encryption info for image %s", image.Name)
+				}
+				fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%s\t%t\t\n",
+					image.Name,
+					image.Target.MediaType,
+					image.Target.Digest,
+					progress.Bytes(size),
+					platformColumn,
+					labels,
+					info.Encrypted)
+				continue
+			}
+
 			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%s\t\n",
 				image.Name,
 				image.Target.MediaType,
@@ -213,11 +260,17 @@ var checkCommand = cli.Command{
 			Aliases: []string{"q"},
 			Usage:   "print only the ready image refs (fully downloaded and unpacked)",
 		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: table (default), wide, json, or a Go template; includes per-layer encryption metadata",
+			Value: format.Table,
+		},
 	}, commands.SnapshotterFlags...),
 	Action: func(context *cli.Context) error {
 		var (
 			exitErr error
 			quiet   = context.Bool("quiet")
+			f       = context.String("format")
 		)
 		client, ctx, cancel, err := commands.NewClient(context)
 		if err != nil {
@@ -237,9 +290,42 @@ var checkCommand = cli.Command{
 			return exitErr
 		}
 
+		if f == format.JSON || format.IsTemplate(f) {
+			checks := make([]format.CheckInfo, 0, len(imageList))
+			for _, image := range imageList {
+				info, err := buildImageInfo(ctx, client, image.Metadata())
+				if err != nil {
+					return err
+				}
+				unpacked, _ := image.IsUnpacked(ctx, context.String("snapshotter"))
+				available, required, present, missing, checkErr := images.Check(ctx, contentStore, image.Target(), platforms.Default())
+				var status string
+				switch {
+				case checkErr != nil:
+					status = "error"
+				case len(missing) > 0:
+					status = fmt.Sprintf("incomplete (%v/%v)", len(present), len(required))
+				case available:
+					status = fmt.Sprintf("complete (%v/%v)", len(present), len(required))
+				default:
+					status = fmt.Sprintf("unavailable (%v/?)", len(present))
+				}
+				checks = append(checks, format.CheckInfo{ImageInfo: info, Status: status, Unpacked: unpacked})
+			}
+			if f == format.JSON {
+				return format.WriteJSON(os.Stdout, checks)
+			}
+			return format.WriteTemplate(os.Stdout, f, checks)
+		}
+
+		wide := f == format.Wide
 		var tw = tabwriter.NewWriter(os.Stdout, 1, 8, 1, ' ', 0)
 		if !quiet {
-			fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSTATUS\tSIZE\tUNPACKED\t")
+			if wide {
+				fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSTATUS\tSIZE\tUNPACKED\tENCRYPTED\t")
+			} else {
+				fmt.Fprintln(tw, "REF\tTYPE\tDIGEST\tSTATUS\tSIZE\tUNPACKED\t")
+			}
 		}
 
 		for _, image := range imageList {
@@ -296,13 +382,28 @@ var checkCommand = cli.Command{
 			}
 
 			if !quiet {
-				fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%t\n",
-					image.Name(),
-					image.Target().MediaType,
-					image.Target().Digest,
-					status,
-					size,
-					unpacked)
+				if wide {
+					info, err := buildImageInfo(ctx, client, image.Metadata())
+					if err != nil {
+						log.G(ctx).WithError(err).Errorf("failed resolving encryption info for image %s", image.Name())
+					}
+					fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%t\t%t\n",
+						image.Name(),
+						image.Target().MediaType,
+						image.Target().Digest,
+						status,
+						size,
+						unpacked,
+						info.Encrypted)
+				} else {
+					fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%t\n",
+						image.Name(),
+						image.Target().MediaType,
+						image.Target().Digest,
+						status,
+						size,
+						unpacked)
+				}
 			} else {
 				if complete {
 					fmt.Println(image.Name())
@@ -20,14 +20,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/cmd/ctr/commands"
+	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/images/archive"
 	"github.com/containerd/imgcrypt"
 	"github.com/containerd/imgcrypt/cmd/ctr/commands/flags"
 	"github.com/containerd/imgcrypt/images/encryption"
+	"github.com/containerd/imgcrypt/images/encryption/keyprovider"
 	"github.com/containerd/imgcrypt/images/encryption/parsehelpers"
 	"github.com/containerd/log"
 	"github.com/containerd/platforms"
@@ -58,6 +61,17 @@ If foobar.tar contains an OCI ref named "latest" and anonymous ref "sha256:deadb
 Import of an encrypted image requires the decryption key to be passed. Even though the image will not be
 decrypted it is required that the user proofs to be in possession of one of the decryption keys needed for
 decrypting the image later on.
+
+Pass --verify-recipients-only to check that proof without unpacking at all: the per-layer symmetric keys are
+unwrapped against the manifest's encrypted-layer annotations, every recipient the unwrapped layers are wrapped
+for is reported (not only the one whose key was supplied), and no plaintext layer content is ever written to
+the snapshotter.
+
+<in> may also be an OCI image layout directory (containing oci-layout, index.json and blobs/sha256/...),
+as produced by "crane" or containers/image's "oci:" transport. In that case blobs are content-addressed-copied
+directly from disk into the content store instead of being repacked into a tar stream, which is considerably
+more efficient for large encrypted images. In that case --base-name only prefixes tag-only refs; full refs are
+imported under their own name whether or not they match it.
 `,
 	Flags: append(append([]cli.Flag{
 		&cli.StringFlag{
@@ -93,6 +107,14 @@ decrypting the image later on.
 			Name:  "compress-blobs",
 			Usage: "compress uncompressed blobs when creating manifest (Docker format only)",
 		},
+		&cli.StringSliceFlag{
+			Name:  "decryption-keyprovider",
+			Usage: "delegate decryption key unwrap to an ocicrypt keyprovider by name (repeatable); providers are looked up in " + keyprovider.DefaultConfigPath,
+		},
+		&cli.BoolFlag{
+			Name:  "verify-recipients-only",
+			Usage: "verify that the supplied keys/keyproviders can unwrap the image, report the recipients of the layers that unwrapped, and skip unpack entirely (implies --no-unpack)",
+		},
 	}, commands.SnapshotterFlags...), flags.ImageDecryptionFlags...),
 
 	Action: func(context *cli.Context) error {
@@ -146,30 +168,90 @@ decrypting the image later on.
 		}
 		defer cancel()
 
-		var r io.ReadCloser
-		if in == "-" {
-			r = os.Stdin
+		var imgs []images.Image
+		if in != "-" && isOCILayout(in) {
+			layoutPlatformMatcher := platformMatcher
+			if layoutPlatformMatcher == nil && !context.Bool("all-platforms") {
+				layoutPlatformMatcher = platforms.Default()
+			}
+			imgs, err = importFromOCILayout(ctx, client, in, importLayoutOptions{
+				Prefix:             prefix,
+				PlatformMatcher:    layoutPlatformMatcher,
+				AllPlatforms:       context.Bool("all-platforms"),
+				Digests:            context.Bool("digests"),
+				SkipDigestForNamed: context.Bool("skip-digest-for-named"),
+			})
+			if err != nil {
+				return err
+			}
 		} else {
-			r, err = os.Open(in)
+			var r io.ReadCloser
+			if in == "-" {
+				r = os.Stdin
+			} else {
+				r, err = os.Open(in)
+				if err != nil {
+					return err
+				}
+			}
+			imgs, err = client.Import(ctx, r, opts...)
+			closeErr := r.Close()
 			if err != nil {
 				return err
 			}
-		}
-		imgs, err := client.Import(ctx, r, opts...)
-		closeErr := r.Close()
-		if err != nil {
-			return err
-		}
-		if closeErr != nil {
-			return closeErr
+			if closeErr != nil {
+				return closeErr
+			}
 		}
 
-		if !context.Bool("no-unpack") {
+		verifyOnly := context.Bool("verify-recipients-only")
+		if !context.Bool("no-unpack") || verifyOnly {
 			cc, err := parsehelpers.CreateDecryptCryptoConfig(ParseEncArgs(context), nil)
 			if err != nil {
 				return err
 			}
 
+			if providers := context.StringSlice("decryption-keyprovider"); len(providers) > 0 {
+				fc, err := keyprovider.LoadConfig(keyprovider.DefaultConfigPath)
+				if err != nil {
+					return err
+				}
+				if err := keyprovider.EnableProviders(providers, fc); err != nil {
+					return fmt.Errorf("unable to enable decryption-keyprovider: %w", err)
+				}
+
+				// ocicrypt forwards DecryptConfig.Parameters verbatim to every
+				// registered keywrapper's unwrap call, including the
+				// provider.<name> ones just registered above; record which
+				// providers were requested so a keyprovider implementation
+				// that inspects them (rather than just its own grpc/cmd
+				// config) sees the selection made on this command line.
+				if cc.DecryptConfig.Parameters == nil {
+					cc.DecryptConfig.Parameters = map[string][][]byte{}
+				}
+				for _, p := range providers {
+					cc.DecryptConfig.Parameters["decryption-keyprovider"] = append(cc.DecryptConfig.Parameters["decryption-keyprovider"], []byte(p))
+				}
+			}
+
+			if verifyOnly {
+				for _, img := range imgs {
+					unwrapped, recipients, err := encryption.VerifyRecipients(ctx, client.ContentStore(), img.Target, cc.DecryptConfig)
+					if err != nil {
+						return fmt.Errorf("unable to verify recipients for %v: %w", img.Name, err)
+					}
+					if !unwrapped {
+						return fmt.Errorf("%v: none of the supplied keys/keyproviders can unwrap this image", img.Name)
+					}
+					if len(recipients) == 0 {
+						fmt.Printf("%v: decryptable (no recoverable recipient identity for this wrap scheme)\n", img.Name)
+						continue
+					}
+					fmt.Printf("%v: decryptable by recipient(s) %v\n", img.Name, strings.Join(recipients, ", "))
+				}
+				return nil
+			}
+
 			ltdd := imgcrypt.Payload{
 				DecryptConfig: *cc.DecryptConfig,
 			}
@@ -0,0 +1,100 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/containerd/containerd/v2/cmd/ctr/commands"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/imgcrypt/internal/format"
+	"github.com/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/urfave/cli/v2"
+)
+
+// inspectCommand returns the full manifest, config and per-layer encryption
+// descriptor for a single image ref as JSON (or a Go template, for scripts
+// that only need a subset), unlike "images list --format json" which
+// reports a summary across every image known to containerd.
+var inspectCommand = cli.Command{
+	Name:      "inspect",
+	Usage:     "display detailed, encryption-aware information about an image",
+	ArgsUsage: "[flags] <ref>",
+	Description: `Display the manifest, config and per-layer encryption descriptor (media
+type, "+encrypted" suffix, wrap scheme, recipient key IDs) for a single
+image, analogous to "docker image inspect" or "crane manifest".
+`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: json (default), or a Go template",
+			Value: format.JSON,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ref := context.Args().First()
+		if ref == "" {
+			return fmt.Errorf("please specify an image reference")
+		}
+
+		client, ctx, cancel, err := commands.NewClient(context)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		imageStore := client.ImageService()
+		image, err := imageStore.Get(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("unable to resolve %v: %w", ref, err)
+		}
+
+		info, err := buildImageInfo(ctx, client, image)
+		if err != nil {
+			return err
+		}
+
+		out := struct {
+			format.ImageInfo
+			Manifest *ocispec.Manifest `json:"manifest,omitempty"`
+			Config   json.RawMessage   `json:"config,omitempty"`
+		}{}
+		cs := client.ContentStore()
+		manifest, err := images.Manifest(ctx, cs, image.Target, platforms.Default())
+		if err == nil {
+			info.Size = 0
+			for _, l := range manifest.Layers {
+				info.Size += l.Size
+			}
+			out.Manifest = &manifest
+			if config, err := content.ReadBlob(ctx, cs, manifest.Config); err == nil {
+				out.Config = config
+			}
+		}
+		out.ImageInfo = info
+
+		f := context.String("format")
+		if f == format.JSON || f == "" {
+			return format.WriteJSON(os.Stdout, out)
+		}
+		return format.WriteTemplate(os.Stdout, f, out)
+	},
+}
@@ -0,0 +1,307 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// refNameAnnotation is the OCI image-layout annotation carrying a manifest's
+// ref, mirroring ocispec.AnnotationRefName.
+const refNameAnnotation = ocispec.AnnotationRefName
+
+// importLayoutOptions mirrors the subset of "images import"'s tar-stream
+// flags that also make sense against an OCI image layout directory.
+// --index-name has no equivalent here: the layout's index.json is a plain
+// list of top-level manifests, not itself a single addressable descriptor
+// the way a tar stream's root index blob is.
+type importLayoutOptions struct {
+	Prefix             string
+	PlatformMatcher    platforms.MatchComparer
+	AllPlatforms       bool
+	Digests            bool
+	SkipDigestForNamed bool
+}
+
+// isOCILayout reports whether in is a directory laid out per the OCI Image
+// Layout spec, as produced by "crane" and consumed by containers/image's
+// "oci:" transport. Only the oci-layout and index.json files are checked for;
+// the blobs directory is not. "images import" uses it to decide whether to
+// stream blobs directly from disk instead of reading a tar stream.
+func isOCILayout(in string) bool {
+	fi, err := os.Stat(in)
+	if err != nil || !fi.IsDir() {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(in, "oci-layout")); err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(in, "index.json")); err != nil {
+		return false
+	}
+	return true
+}
+
+// readOCILayoutIndex parses the top-level index.json of an OCI image layout
+// directory.
+func readOCILayoutIndex(in string) (ocispec.Index, error) {
+	var idx ocispec.Index
+	data, err := os.ReadFile(filepath.Join(in, "index.json"))
+	if err != nil {
+		return idx, fmt.Errorf("unable to read index.json: %w", err)
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return idx, fmt.Errorf("unable to parse index.json: %w", err)
+	}
+	return idx, nil
+}
+
+// importFromOCILayout copies every blob matching opts' platform filter into
+// the content store, then registers an images.Image for each top-level
+// manifest per opts, the same way "images import" names refs found in a tar
+// stream (base-name prefix for tag-only refs, full refs kept as they are,
+// optional digest refs, skip-digest-for-named). Unlike the tar-stream path,
+// full refs outside --base-name are not filtered out.
+func importFromOCILayout(ctx context.Context, client *containerd.Client, in string, opts importLayoutOptions) ([]images.Image, error) {
+	idx, err := readOCILayoutIndex(in)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := client.ContentStore()
+	imageStore := client.ImageService()
+
+	var imgs []images.Image
+	for _, desc := range idx.Manifests {
+		if err := copyOCILayoutBlobs(ctx, cs, in, desc, opts.PlatformMatcher, opts.AllPlatforms); err != nil {
+			return nil, fmt.Errorf("unable to import %v: %w", desc.Digest, err)
+		}
+
+		name, hasName := desc.Annotations[refNameAnnotation]
+		hasName = hasName && name != ""
+
+		if hasName {
+			if opts.Digests && !opts.SkipDigestForNamed {
+				img, err := createOCILayoutImage(ctx, imageStore, digestImageName(opts.Prefix, desc), desc)
+				if err != nil {
+					return nil, err
+				}
+				imgs = append(imgs, img)
+			}
+			ref := name
+			if !strings.ContainsAny(name, "/:@") {
+				ref = fmt.Sprintf("%s:%s", opts.Prefix, name)
+			}
+			img, err := createOCILayoutImage(ctx, imageStore, ref, desc)
+			if err != nil {
+				return nil, err
+			}
+			imgs = append(imgs, img)
+			continue
+		}
+
+		if opts.Digests {
+			img, err := createOCILayoutImage(ctx, imageStore, digestImageName(opts.Prefix, desc), desc)
+			if err != nil {
+				return nil, err
+			}
+			imgs = append(imgs, img)
+		}
+	}
+	return imgs, nil
+}
+
+// digestImageName mirrors archive.DigestTranslator's "prefix@digest" naming
+// for the digest ref created alongside a named one when --digests is set.
+func digestImageName(prefix string, desc ocispec.Descriptor) string {
+	return fmt.Sprintf("%s@%s", prefix, desc.Digest)
+}
+
+func createOCILayoutImage(ctx context.Context, imageStore images.Store, name string, desc ocispec.Descriptor) (images.Image, error) {
+	created, err := imageStore.Create(ctx, images.Image{Name: name, Target: desc})
+	if err != nil {
+		return images.Image{}, fmt.Errorf("unable to create image %v: %w", name, err)
+	}
+	return created, nil
+}
+
+// copyOCILayoutBlobs content-addressed-copies desc and, recursively, every
+// blob it references into cs, reading each blob from disk at
+// in/blobs/<alg>/<encoded> per the OCI Image Layout spec. Once a blob has
+// been written, images.Children(ctx, cs, desc) parses its actual manifest or
+// index structure to discover what to copy next, rather than re-implementing
+// that parsing here. For a multi-platform index, children representing a
+// non-matching platform are skipped unless allPlatforms is set.
+func copyOCILayoutBlobs(ctx context.Context, cs content.Store, in string, desc ocispec.Descriptor, platformMatcher platforms.MatchComparer, allPlatforms bool) error {
+	if err := copyOCILayoutBlob(ctx, cs, in, desc); err != nil {
+		return err
+	}
+
+	children, err := images.Children(ctx, cs, desc)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// desc is a leaf blob (layer or config): nothing more to walk.
+			return nil
+		}
+		return err
+	}
+
+	for _, child := range children {
+		if desc.MediaType == ocispec.MediaTypeImageIndex && !allPlatforms && platformMatcher != nil {
+			if child.Platform != nil && !platformMatcher.Match(*child.Platform) {
+				continue
+			}
+		}
+		if err := copyOCILayoutBlobs(ctx, cs, in, child, platformMatcher, allPlatforms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyOCILayoutBlob(ctx context.Context, cs content.Store, in string, desc ocispec.Descriptor) error {
+	blobPath := filepath.Join(in, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	f, err := os.Open(blobPath)
+	if err != nil {
+		return fmt.Errorf("unable to open blob %v: %w", desc.Digest, err)
+	}
+	defer f.Close()
+
+	if err := content.WriteBlob(ctx, cs, desc.Digest.String(), f, desc); err != nil {
+		return fmt.Errorf("unable to write blob %v: %w", desc.Digest, err)
+	}
+	return nil
+}
+
+// exportOCILayout writes imgs and every blob they reference into an OCI
+// Image Layout directory at out, the read-direction counterpart to
+// copyOCILayoutBlobs/importFromOCILayout. Each top-level manifest is
+// annotated with its containerd image name so a later "images import" of
+// the same directory recovers it as a named ref.
+func exportOCILayout(ctx context.Context, client *containerd.Client, out string, imgs []images.Image, platformMatcher platforms.MatchComparer, allPlatforms bool) error {
+	if err := os.MkdirAll(filepath.Join(out, "blobs"), 0755); err != nil {
+		return fmt.Errorf("unable to create %v: %w", out, err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644); err != nil {
+		return fmt.Errorf("unable to write oci-layout: %w", err)
+	}
+
+	cs := client.ContentStore()
+	seen := map[digest.Digest]struct{}{}
+	idx := ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	idx.SchemaVersion = 2
+
+	for _, img := range imgs {
+		if err := writeOCILayoutBlobs(ctx, cs, out, img.Target, platformMatcher, allPlatforms, seen); err != nil {
+			return fmt.Errorf("unable to export %v: %w", img.Name, err)
+		}
+
+		desc := img.Target
+		annotations := make(map[string]string, len(desc.Annotations)+1)
+		for k, v := range desc.Annotations {
+			annotations[k] = v
+		}
+		annotations[refNameAnnotation] = img.Name
+		desc.Annotations = annotations
+		idx.Manifests = append(idx.Manifests, desc)
+	}
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal index.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "index.json"), data, 0644); err != nil {
+		return fmt.Errorf("unable to write index.json: %w", err)
+	}
+	return nil
+}
+
+// writeOCILayoutBlobs is copyOCILayoutBlobs' mirror image: it reads desc and
+// its children from cs and writes them to out/blobs/<alg>/<encoded>, using
+// images.Children to discover what to walk next rather than re-parsing
+// manifest/index JSON by hand. seen dedupes blobs shared between images
+// (e.g. a common base layer) so they are only written once.
+func writeOCILayoutBlobs(ctx context.Context, cs content.Provider, out string, desc ocispec.Descriptor, platformMatcher platforms.MatchComparer, allPlatforms bool, seen map[digest.Digest]struct{}) error {
+	if _, ok := seen[desc.Digest]; ok {
+		return nil
+	}
+	seen[desc.Digest] = struct{}{}
+
+	if err := writeOCILayoutBlob(ctx, cs, out, desc); err != nil {
+		return err
+	}
+
+	children, err := images.Children(ctx, cs, desc)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, child := range children {
+		if desc.MediaType == ocispec.MediaTypeImageIndex && !allPlatforms && platformMatcher != nil {
+			if child.Platform != nil && !platformMatcher.Match(*child.Platform) {
+				continue
+			}
+		}
+		if err := writeOCILayoutBlobs(ctx, cs, out, child, platformMatcher, allPlatforms, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOCILayoutBlob(ctx context.Context, cs content.Provider, out string, desc ocispec.Descriptor) error {
+	dir := filepath.Join(out, "blobs", desc.Digest.Algorithm().String())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	ra, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("unable to read blob %v: %w", desc.Digest, err)
+	}
+	defer ra.Close()
+
+	f, err := os.Create(filepath.Join(dir, desc.Digest.Encoded()))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, content.NewReader(ra)); err != nil {
+		return fmt.Errorf("unable to write blob %v: %w", desc.Digest, err)
+	}
+	return nil
+}
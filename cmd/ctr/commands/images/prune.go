@@ -0,0 +1,302 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/cmd/ctr/commands"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/imgcrypt/internal/format"
+	"github.com/containerd/log"
+	"github.com/opencontainers/go-digest"
+	"github.com/urfave/cli/v2"
+)
+
+// pruneFilter is a single parsed `--filter` predicate. Predicates within a
+// single invocation are AND-ed together, matching Podman's libimage prune
+// semantics.
+type pruneFilter struct {
+	danglingSet   bool
+	dangling      bool
+	until         time.Time
+	label         string
+	labelValue    string
+	hasLabelValue bool
+	reference     string
+	encryptedSet  bool
+	encrypted     bool
+	wrappedForKey string
+}
+
+var pruneCommand = cli.Command{
+	Name:      "prune",
+	Usage:     "remove unused images",
+	ArgsUsage: "[flags]",
+	Description: `Remove images that are not referenced by any container and match the
+given filters. With no --filter given, this defaults to "dangling=true", the
+same default Podman's libimage prune uses, rather than removing every image.
+
+In addition to the usual "dangling", "until", "label" and "reference" filters,
+imgcrypt adds two predicates that inspect the manifest's layer media types and
+wrapped-key annotations:
+
+  --filter encrypted=true|false   only match images whose layers do (not) carry
+                                   the "+encrypted" media type suffix
+  --filter wrapped-for=<id>       only match encrypted images that are wrapped
+                                   for the given recipient: a 16-digit hex pgp
+                                   key ID, or a pkcs7 recipient certificate
+                                   subject. jwe-wrapped layers carry no
+                                   recoverable recipient identity, so this
+                                   never matches a jwe-only image.
+`,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "filter images to be pruned (default: dangling=true; e.g. until=24h, label=k=v, reference=foo*, encrypted=true, wrapped-for=keyid)",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "report what would be deleted without removing anything",
+		},
+		&cli.BoolFlag{
+			Name:  "sync",
+			Usage: "synchronously remove images and all associated resources",
+		},
+		&cli.BoolFlag{
+			Name:    "force",
+			Aliases: []string{"f"},
+			Usage:   "do not prompt for confirmation",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		rawFilters := context.StringSlice("filter")
+		if len(rawFilters) == 0 {
+			rawFilters = []string{"dangling=true"}
+		}
+		filters, err := parsePruneFilters(rawFilters)
+		if err != nil {
+			return err
+		}
+
+		client, ctx, cancel, err := commands.NewClient(context)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		imageStore := client.ImageService()
+		imageList, err := imageStore.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list images: %w", err)
+		}
+
+		containerList, err := client.ContainerService().List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list containers: %w", err)
+		}
+		inUse := map[string]struct{}{}
+		for _, c := range containerList {
+			inUse[c.Image] = struct{}{}
+		}
+
+		var toDelete []images.Image
+		for _, image := range imageList {
+			if _, ok := inUse[image.Name]; ok {
+				continue
+			}
+			match, err := matchesPruneFilters(ctx, client, image, filters)
+			if err != nil {
+				log.G(ctx).WithError(err).Errorf("unable to evaluate filters for %v", image.Name)
+				continue
+			}
+			if match {
+				toDelete = append(toDelete, image)
+			}
+		}
+
+		dryRun := context.Bool("dry-run")
+		if dryRun {
+			for _, image := range toDelete {
+				fmt.Println(image.Target.Digest.String())
+			}
+			return nil
+		}
+
+		if len(toDelete) == 0 {
+			return nil
+		}
+
+		if !context.Bool("force") {
+			confirmed, err := confirmPrune(os.Stdin, os.Stdout, toDelete)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return nil
+			}
+		}
+
+		var opts []images.DeleteOpt
+		if context.Bool("sync") {
+			opts = append(opts, images.SynchronousDelete())
+		}
+
+		var exitErr error
+		for _, image := range toDelete {
+			if err := imageStore.Delete(ctx, image.Name, opts...); err != nil {
+				if exitErr == nil {
+					exitErr = fmt.Errorf("unable to delete %v: %w", image.Name, err)
+				}
+				log.G(ctx).WithError(err).Errorf("unable to delete %v", image.Name)
+				continue
+			}
+			fmt.Println(image.Target.Digest.String())
+		}
+
+		return exitErr
+	},
+}
+
+// confirmPrune prints the images about to be removed and asks for
+// confirmation on in, mirroring Podman's "WARNING! This will remove..."
+// prompt for its own prune commands.
+func confirmPrune(in *os.File, out *os.File, toDelete []images.Image) (bool, error) {
+	fmt.Fprintf(out, "WARNING! This will remove %d image(s) from containerd.\n", len(toDelete))
+	for _, image := range toDelete {
+		fmt.Fprintf(out, "  %v (%v)\n", image.Name, image.Target.Digest)
+	}
+	fmt.Fprint(out, "Are you sure you want to continue? [y/N] ")
+
+	scanner := bufio.NewScanner(in)
+	if !scanner.Scan() {
+		return false, nil
+	}
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes", nil
+}
+
+func parsePruneFilters(raw []string) ([]pruneFilter, error) {
+	var filters []pruneFilter
+	for _, f := range raw {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter %q: expected key=value", f)
+		}
+
+		var pf pruneFilter
+		switch k {
+		case "dangling":
+			pf.danglingSet = true
+			pf.dangling = v == "true"
+		case "until":
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid until filter %q: %w", v, err)
+			}
+			pf.until = time.Now().Add(-d)
+		case "label":
+			lk, lv, hasValue := strings.Cut(v, "=")
+			pf.label = lk
+			pf.labelValue = lv
+			pf.hasLabelValue = hasValue
+		case "reference":
+			pf.reference = v
+		case "encrypted":
+			pf.encryptedSet = true
+			pf.encrypted = v == "true"
+		case "wrapped-for":
+			pf.wrappedForKey = v
+		default:
+			return nil, fmt.Errorf("unrecognized filter key %q", k)
+		}
+		filters = append(filters, pf)
+	}
+	return filters, nil
+}
+
+func matchesPruneFilters(ctx context.Context, client *containerd.Client, image images.Image, filters []pruneFilter) (bool, error) {
+	for _, f := range filters {
+		switch {
+		case f.danglingSet:
+			if isDanglingRef(image.Name) != f.dangling {
+				return false, nil
+			}
+		case !f.until.IsZero():
+			if image.CreatedAt.After(f.until) {
+				return false, nil
+			}
+		case f.label != "":
+			v, ok := image.Labels[f.label]
+			if !ok {
+				return false, nil
+			}
+			if f.hasLabelValue && v != f.labelValue {
+				return false, nil
+			}
+		case f.reference != "":
+			matched, err := filepath.Match(f.reference, image.Name)
+			if err != nil || !matched {
+				return false, nil
+			}
+		case f.encryptedSet:
+			info, err := buildImageInfo(ctx, client, image)
+			if err != nil {
+				return false, err
+			}
+			if info.Encrypted != f.encrypted {
+				return false, nil
+			}
+		case f.wrappedForKey != "":
+			info, err := buildImageInfo(ctx, client, image)
+			if err != nil {
+				return false, err
+			}
+			if !imageHasRecipient(info.Layers, f.wrappedForKey) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// isDanglingRef reports whether name is a raw digest reference rather than a
+// human-assigned tag, i.e. nothing else refers to this image by name.
+func isDanglingRef(name string) bool {
+	_, err := digest.Parse(name)
+	return err == nil
+}
+
+// imageHasRecipient reports whether any encrypted layer is wrapped for the
+// given recipient/key ID, as reported by encryption.GetLayerRecipients.
+func imageHasRecipient(layers []format.LayerInfo, want string) bool {
+	for _, l := range layers {
+		for _, r := range l.Recipients {
+			if r == want {
+				return true
+			}
+		}
+	}
+	return false
+}
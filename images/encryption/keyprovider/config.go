@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package keyprovider loads ocicrypt's keyproviders.conf and registers the
+// providers "ctr images import --decryption-keyprovider" asks for with
+// ocicrypt's own keywrap registry, so that key unwrap can be delegated to an
+// external process (Vault, KMIP, AWS-KMS, ...) instead of requiring private
+// key material on disk. It supports both transports described by ocicrypt's
+// keyprovider spec: a gRPC service reached over a unix or tcp socket, and a
+// one-shot exec/binary command that exchanges requests on stdin/stdout.
+package keyprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/containers/ocicrypt"
+	keyproviderconfig "github.com/containers/ocicrypt/config/keyprovider-config"
+	ocikeyprovider "github.com/containers/ocicrypt/keywrap/keyprovider"
+)
+
+// DefaultConfigPath is the well-known location ocicrypt-aware tools read
+// keyprovider definitions from, mirroring ocicrypt's own keyproviders.conf
+// convention.
+const DefaultConfigPath = "/etc/containerd/ocicrypt/keyproviders.conf"
+
+// LoadConfig reads and parses a keyproviders.conf file. A missing file at
+// path is not an error; it simply yields an empty OcicryptConfig so that
+// --decryption-keyprovider fails with a clear "unknown keyprovider" error
+// rather than an opaque file-not-found one.
+func LoadConfig(path string) (keyproviderconfig.OcicryptConfig, error) {
+	var fc keyproviderconfig.OcicryptConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fc, nil
+		}
+		return fc, fmt.Errorf("unable to read keyprovider config %v: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return fc, fmt.Errorf("unable to parse keyprovider config %v: %w", path, err)
+	}
+	return fc, nil
+}
+
+// EnableProviders registers the named providers from fc under the
+// "provider.<name>" keywrap scheme ocicrypt dispatches encrypted-layer
+// annotations by, so that a layer wrapped with
+// "org.opencontainers.image.enc.keys.provider.<name>" is routed to the
+// configured gRPC/exec transport. ocicrypt has no way to scope a
+// registration to a single CryptoConfig: once registered, a provider is
+// available to every subsequent wrap/unwrap in this process, so only the
+// providers actually named on the command line are registered rather than
+// the whole file.
+func EnableProviders(names []string, fc keyproviderconfig.OcicryptConfig) error {
+	for _, name := range names {
+		attrs, ok := fc.KeyProviderConfig[name]
+		if !ok {
+			return fmt.Errorf("unknown keyprovider %q: not found in %v", name, DefaultConfigPath)
+		}
+		ocicrypt.RegisterKeyWrapper("provider."+name, ocikeyprovider.NewKeyWrapper(name, attrs))
+	}
+	return nil
+}
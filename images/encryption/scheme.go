@@ -0,0 +1,89 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/containers/ocicrypt/keywrap/pgp"
+	"github.com/containers/ocicrypt/keywrap/pkcs7"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// encKeysAnnotationPrefix is the per-scheme wrapped-key annotation ocicrypt
+// attaches to an encrypted layer descriptor, e.g.
+// "org.opencontainers.image.enc.keys.jwe".
+const encKeysAnnotationPrefix = "org.opencontainers.image.enc.keys."
+
+// knownSchemes are checked in a fixed order so GetLayerScheme is
+// deterministic if a layer were ever (incorrectly) wrapped for more than one
+// scheme at once.
+var knownSchemes = []string{"jwe", "pgp", "pkcs7"}
+
+// GetLayerScheme returns the wrap scheme (jwe, pgp or pkcs7) used by an
+// encrypted layer descriptor, or "" if none of the known per-scheme
+// annotations are present.
+func GetLayerScheme(desc ocispec.Descriptor) string {
+	for _, scheme := range knownSchemes {
+		if _, ok := desc.Annotations[encKeysAnnotationPrefix+scheme]; ok {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// GetLayerRecipients extracts the recipient identities a layer is wrapped
+// for, where recoverable without decrypting:
+//
+//   - pgp: the annotation is base64 of a binary GPG wrapped-key packet;
+//     pgp.GetKeyIdsFromPacket recovers the recipient key IDs, reported as
+//     16-digit hex (the conventional long-key-ID form).
+//   - pkcs7: the annotation is base64 of a PKCS7 structure;
+//     pkcs7.GetRecipients recovers the recipient certificate subjects.
+//   - jwe: ocicrypt's jwe keywrapper sets no per-recipient "kid" (or other
+//     identifying) header, so recipient identity cannot be recovered from
+//     the manifest alone; this always returns nil for jwe-wrapped layers.
+func GetLayerRecipients(desc ocispec.Descriptor) []string {
+	switch GetLayerScheme(desc) {
+	case "pgp":
+		raw, ok := desc.Annotations[encKeysAnnotationPrefix+"pgp"]
+		if !ok {
+			return nil
+		}
+		keyIDs, err := pgp.GetKeyIdsFromPacket(raw)
+		if err != nil {
+			return nil
+		}
+		ids := make([]string, 0, len(keyIDs))
+		for _, id := range keyIDs {
+			ids = append(ids, fmt.Sprintf("%016x", id))
+		}
+		return ids
+	case "pkcs7":
+		raw, ok := desc.Annotations[encKeysAnnotationPrefix+"pkcs7"]
+		if !ok {
+			return nil
+		}
+		ids, err := pkcs7.GetRecipients(raw)
+		if err != nil {
+			return nil
+		}
+		return ids
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/platforms"
+	"github.com/containers/ocicrypt"
+	encconfig "github.com/containers/ocicrypt/config"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// VerifyRecipients walks every encrypted layer in desc's manifest and
+// attempts to unwrap its per-layer symmetric key against dc, using
+// ocicrypt.DecryptLayer's unwrapOnly mode so no layer content is ever read
+// or decrypted. It reports whether proof of possession of a decryption key
+// succeeded for at least one layer, plus the recipient key IDs recovered
+// from the layers that unwrapped (per GetLayerRecipients, deduplicated in
+// first-seen order). Recipients may be empty even when unwrapped is true:
+// ocicrypt's jwe keywrapper carries no recoverable recipient identity, and
+// neither does a keyprovider-wrapped layer, so such an image unwraps
+// successfully but yields no recipient IDs. A manifest with no encrypted
+// layers at all also returns unwrapped=false and no error; the return values
+// alone do not tell "not encrypted" apart from "found no matching key".
+func VerifyRecipients(ctx context.Context, cs content.Provider, desc ocispec.Descriptor, dc *encconfig.DecryptConfig) (unwrapped bool, recipients []string, err error) {
+	manifest, err := images.Manifest(ctx, cs, desc, platforms.Default())
+	if err != nil {
+		return false, nil, fmt.Errorf("unable to read manifest: %w", err)
+	}
+
+	seen := map[string]struct{}{}
+	for _, layer := range manifest.Layers {
+		if !strings.HasSuffix(layer.MediaType, "+encrypted") {
+			continue
+		}
+		if _, _, err := ocicrypt.DecryptLayer(dc, nil, layer, true); err != nil {
+			continue
+		}
+		unwrapped = true
+		for _, id := range GetLayerRecipients(layer) {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			recipients = append(recipients, id)
+		}
+	}
+	return unwrapped, recipients, nil
+}
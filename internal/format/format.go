@@ -0,0 +1,109 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package format provides the shared --format flag handling used by
+// "images list", "images check" and "images inspect": "table" (the
+// tabwriter-rendered default), "wide" (table plus extra columns), "json",
+// or a Go template string in the style of "docker inspect --format"/"crane
+// manifest".
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+)
+
+// Table, Wide and JSON are the reserved --format values; anything else is
+// treated as a Go template string.
+const (
+	Table = "table"
+	Wide  = "wide"
+	JSON  = "json"
+)
+
+// LayerInfo is the encryption-aware description of a single image layer.
+type LayerInfo struct {
+	MediaType  string   `json:"mediaType"`
+	Encrypted  bool     `json:"encrypted"`
+	Scheme     string   `json:"scheme,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// ImageInfo is the stable, machine-readable description of an image emitted
+// by "images list --format json", "images check --format json" and
+// "images inspect".
+type ImageInfo struct {
+	Ref       string            `json:"ref"`
+	Digest    string            `json:"digest"`
+	Size      int64             `json:"size"`
+	Platforms []string          `json:"platforms,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Encrypted bool              `json:"encrypted"`
+	Layers    []LayerInfo       `json:"layers,omitempty"`
+}
+
+// CheckInfo extends ImageInfo with the availability/unpack status reported
+// by "images check --format".
+type CheckInfo struct {
+	ImageInfo
+	Status   string `json:"status"`
+	Unpacked bool   `json:"unpacked"`
+}
+
+// IsTemplate reports whether format names neither the table/wide renderer
+// the caller already knows how to draw, nor JSON, and should instead be
+// compiled as a Go template.
+func IsTemplate(format string) bool {
+	switch format {
+	case "", Table, Wide, JSON:
+		return false
+	default:
+		return true
+	}
+}
+
+// WriteJSON marshals v (typically a []ImageInfo or ImageInfo) as indented
+// JSON.
+func WriteJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteTemplate renders v through the given Go template string, one
+// execution per element if v is a slice, matching "docker inspect"'s
+// behavior of applying --format per-object rather than to the whole list.
+func WriteTemplate(w io.Writer, format string, v interface{}) error {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return tmpl.Execute(w, v)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := tmpl.Execute(w, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}